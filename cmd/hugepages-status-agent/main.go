@@ -0,0 +1,149 @@
+// Command hugepages-status-agent runs as a DaemonSet Pod on every node
+// matched by a PerformanceProfile's NodeSelector. It periodically reads the
+// node's hugepage counters and per-NUMA-node memory capacity out of sysfs
+// (procfs is mounted at /host-proc but /proc/meminfo is not read) and
+// annotates the Node object with the result, so that the performanceprofile controller can
+// aggregate it onto PerformanceProfile.Status.HugepagesStatus.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/hugepagesstatus"
+	"github.com/openshift-kni/performance-addon-operators/pkg/hugepages/sysfs"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+)
+
+const (
+	sysRoot  = "/host-sys"
+	procRoot = "/host-proc"
+
+	reportInterval = time.Minute
+)
+
+func main() {
+	nodeName := os.Getenv("NODE_NAME")
+	if nodeName == "" {
+		klog.Fatalf("NODE_NAME environment variable must be set")
+	}
+
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		klog.Fatalf("failed to build in-cluster config: %v", err)
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		klog.Fatalf("failed to build kubernetes client: %v", err)
+	}
+
+	for {
+		if err := report(client, nodeName); err != nil {
+			klog.Errorf("failed to report hugepages status for node %q: %v", nodeName, err)
+		}
+		time.Sleep(reportInterval)
+	}
+}
+
+func report(client kubernetes.Interface, nodeName string) error {
+	status, err := collect()
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hugepages status: %v", err)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				hugepagesstatus.StatusAnnotationKey: string(raw),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal annotation patch: %v", err)
+	}
+
+	_, err = client.CoreV1().Nodes().Patch(context.TODO(), nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func collect() (v2.NodeHugepagesStatus, error) {
+	status := v2.NodeHugepagesStatus{}
+
+	global, err := sysfs.ReadGlobal(sysRoot)
+	if err != nil {
+		return status, err
+	}
+	for sizeKB, stats := range global {
+		status.Sizes = append(status.Sizes, toSizeStatus(sizeKB, stats))
+	}
+
+	numa, err := sysfs.ReadNUMA(sysRoot)
+	if err != nil {
+		return status, err
+	}
+
+	memTotal, err := sysfs.ReadNUMAMemTotal(sysRoot)
+	if err != nil {
+		return status, err
+	}
+
+	for numaID, sizes := range numa {
+		numaStatus := v2.NUMAHugepagesStatus{NUMANodeID: numaID, MemTotalKB: memTotal[numaID]}
+		for sizeKB, stats := range sizes {
+			numaStatus.Sizes = append(numaStatus.Sizes, toSizeStatus(sizeKB, stats))
+		}
+		status.NUMA = append(status.NUMA, numaStatus)
+	}
+
+	return status, nil
+}
+
+func toSizeStatus(sizeKB int64, stats sysfs.SizeStats) v2.HugePageSizeStatus {
+	return v2.HugePageSizeStatus{
+		Size:     kbToHugePageSize(sizeKB),
+		Total:    int32(stats.Total),
+		Free:     int32(stats.Free),
+		Surplus:  int32(stats.Surplus),
+		Reserved: int32(stats.Reserved),
+	}
+}
+
+// kbToHugePageSize renders a size in kB the way the profile's HugePage.Size
+// fields are expressed on the agent's architecture, e.g. 2048 -> "2M",
+// 1048576 -> "1G" on amd64, and 2048 -> "2Mi", 1048576 -> "1Gi" elsewhere.
+func kbToHugePageSize(sizeKB int64) v2.HugePageSize {
+	const kbPerM = 1024
+	const kbPerG = 1024 * 1024
+
+	// Only amd64 sizes use the bare "G"/"M"/"K" suffixes; the arm64 and
+	// ppc64le sizes use the binary "Gi"/"Mi"/"Ki" forms.
+	suffix := ""
+	if runtime.GOARCH != "amd64" {
+		suffix = "i"
+	}
+
+	switch {
+	case sizeKB%kbPerG == 0:
+		return v2.HugePageSize(fmt.Sprintf("%dG%s", sizeKB/kbPerG, suffix))
+	case sizeKB%kbPerM == 0:
+		return v2.HugePageSize(fmt.Sprintf("%dM%s", sizeKB/kbPerM, suffix))
+	default:
+		return v2.HugePageSize(fmt.Sprintf("%dK%s", sizeKB, suffix))
+	}
+}
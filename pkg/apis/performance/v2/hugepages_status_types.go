@@ -0,0 +1,59 @@
+package v2
+
+// HugePageSizeStatus reports the observed state of a single hugepage size,
+// either globally on a node or scoped to one of its NUMA nodes.
+type HugePageSizeStatus struct {
+	// Size is the hugepage size this status entry describes, e.g. "2M" or "1G".
+	Size HugePageSize `json:"size"`
+
+	// Total is the number of hugepages of this size currently allocated
+	// (nr_hugepages).
+	Total int32 `json:"total"`
+
+	// Free is the number of allocated hugepages of this size that are not
+	// currently in use (free_hugepages).
+	Free int32 `json:"free"`
+
+	// Surplus is the number of surplus hugepages of this size above
+	// nr_hugepages (surplus_hugepages).
+	Surplus int32 `json:"surplus"`
+
+	// Reserved is the number of hugepages of this size reserved for an
+	// unallocated page fault (resv_hugepages).
+	Reserved int32 `json:"reserved"`
+}
+
+// NUMAHugepagesStatus reports the observed hugepage state of a single NUMA
+// node.
+type NUMAHugepagesStatus struct {
+	// NUMANodeID is the index of the NUMA node, e.g. 0.
+	NUMANodeID int32 `json:"numaNodeID"`
+
+	// MemTotalKB is the total memory capacity of this NUMA node, in kB, as
+	// reported by /sys/devices/system/node/node<N>/meminfo.
+	MemTotalKB int64 `json:"memTotalKB,omitempty"`
+
+	// Sizes is the per-size hugepage status observed on this NUMA node.
+	Sizes []HugePageSizeStatus `json:"sizes,omitempty"`
+}
+
+// NodeHugepagesStatus reports the observed hugepage state of a single node
+// matched by the profile's NodeSelector.
+type NodeHugepagesStatus struct {
+	// NodeName is the name of the node this status was observed on.
+	NodeName string `json:"nodeName"`
+
+	// Sizes is the node-global, per-size hugepage status, aggregated across
+	// NUMA nodes.
+	Sizes []HugePageSizeStatus `json:"sizes,omitempty"`
+
+	// NUMA is the per-NUMA-node breakdown of hugepage status.
+	NUMA []NUMAHugepagesStatus `json:"numa,omitempty"`
+}
+
+// HugepagesStatus reports the hugepage state actually observed on the nodes
+// matched by the profile, as opposed to what Spec.HugePages requested.
+type HugepagesStatus struct {
+	// Nodes is the per-node hugepage status last collected from the cluster.
+	Nodes []NodeHugepagesStatus `json:"nodes,omitempty"`
+}
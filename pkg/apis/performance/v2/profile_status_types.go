@@ -0,0 +1,20 @@
+package v2
+
+import (
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+)
+
+// PerformanceProfileStatus gains a HugepagesStatus field, populated by the
+// hugepagesstatus controller from the state the hugepages-status-agent
+// DaemonSet observes on the nodes the profile matches.
+type PerformanceProfileStatus struct {
+	// Conditions represents the latest available observations of the
+	// profile's current state.
+	// +optional
+	Conditions []conditionsv1.Condition `json:"conditions,omitempty"`
+
+	// HugepagesStatus is the hugepage state last observed on the nodes
+	// matched by the profile's NodeSelector.
+	// +optional
+	HugepagesStatus *HugepagesStatus `json:"hugepagesStatus,omitempty"`
+}
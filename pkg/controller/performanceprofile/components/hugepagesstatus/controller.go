@@ -0,0 +1,82 @@
+package hugepagesstatus
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// StatusAnnotationKey is the Node annotation the hugepages-status-agent
+// DaemonSet writes its observed hugepage state to, as a JSON-encoded
+// NodeHugepagesStatus.
+const StatusAnnotationKey = "performance.openshift.io/hugepages-status"
+
+// ConditionDegradedReasonDrift is used on the PerformanceProfileConditionDegraded
+// condition when the hugepage allocation observed on a matched node does not
+// match what Spec.HugePages requested (e.g. 1Gi pages that require a reboot
+// to take effect and haven't been applied yet).
+const ConditionDegradedReasonDrift = "HugepagesAllocationDrift"
+
+// AggregateStatus reads the hugepages-status-agent annotation off each node
+// matched by the profile and builds the HugepagesStatus to publish on
+// PerformanceProfile.Status. Nodes without the annotation yet (the DaemonSet
+// pod hasn't reported in) are skipped rather than treated as an error.
+func AggregateStatus(nodes []*corev1.Node) (v2.HugepagesStatus, error) {
+	status := v2.HugepagesStatus{}
+
+	for _, node := range nodes {
+		raw, ok := node.Annotations[StatusAnnotationKey]
+		if !ok {
+			continue
+		}
+
+		var nodeStatus v2.NodeHugepagesStatus
+		if err := json.Unmarshal([]byte(raw), &nodeStatus); err != nil {
+			return status, fmt.Errorf("failed to parse %q annotation on node %q: %v", StatusAnnotationKey, node.Name, err)
+		}
+
+		nodeStatus.NodeName = node.Name
+		status.Nodes = append(status.Nodes, nodeStatus)
+	}
+
+	return status, nil
+}
+
+// DetectDrift compares the hugepage allocation requested by profile.Spec.HugePages
+// against the allocation observed in status, and returns a Degraded condition
+// describing the first mismatch found, or nil if the observed state matches
+// every reporting node (or no node has reported yet).
+func DetectDrift(profile *v2.PerformanceProfile, status v2.HugepagesStatus) *conditionsv1.Condition {
+	if profile.Spec.HugePages == nil {
+		return nil
+	}
+
+	requested := map[v2.HugePageSize]int32{}
+	for _, page := range profile.Spec.HugePages.Pages {
+		requested[page.Size] += page.Count
+	}
+
+	for _, node := range status.Nodes {
+		observed := map[v2.HugePageSize]int32{}
+		for _, size := range node.Sizes {
+			observed[size.Size] += size.Total
+		}
+
+		for size, wantCount := range requested {
+			if observed[size] != wantCount {
+				return &conditionsv1.Condition{
+					Type:    conditionsv1.ConditionDegraded,
+					Status:  corev1.ConditionTrue,
+					Reason:  ConditionDegradedReasonDrift,
+					Message: fmt.Sprintf("node %q requested %d hugepages of size %q but %d are allocated; a reboot may be required", node.NodeName, wantCount, size, observed[size]),
+				}
+			}
+		}
+	}
+
+	return nil
+}
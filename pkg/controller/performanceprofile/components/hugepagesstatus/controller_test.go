@@ -0,0 +1,81 @@
+package hugepagesstatus
+
+import (
+	"encoding/json"
+	"testing"
+
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHugepagesStatus(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "hugepagesstatus Suite")
+}
+
+func nodeWithStatus(name string, status v2.NodeHugepagesStatus) *corev1.Node {
+	raw, err := json.Marshal(status)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				StatusAnnotationKey: string(raw),
+			},
+		},
+	}
+}
+
+var _ = Describe("AggregateStatus", func() {
+	It("should skip nodes that have not reported yet", func() {
+		reported := nodeWithStatus("node0", v2.NodeHugepagesStatus{
+			Sizes: []v2.HugePageSizeStatus{{Size: "1G", Total: 4, Free: 4}},
+		})
+		notReported := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+
+		status, err := AggregateStatus([]*corev1.Node{reported, notReported})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status.Nodes).To(HaveLen(1))
+		Expect(status.Nodes[0].NodeName).To(Equal("node0"))
+	})
+})
+
+var _ = Describe("DetectDrift", func() {
+	var profile *v2.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = &v2.PerformanceProfile{
+			Spec: v2.PerformanceProfileSpec{
+				HugePages: &v2.HugePages{
+					Pages: []v2.HugePage{{Size: "1G", Count: 4}},
+				},
+			},
+		}
+	})
+
+	It("should report no drift when the observed allocation matches the request", func() {
+		status := v2.HugepagesStatus{
+			Nodes: []v2.NodeHugepagesStatus{
+				{NodeName: "node0", Sizes: []v2.HugePageSizeStatus{{Size: "1G", Total: 4}}},
+			},
+		}
+		Expect(DetectDrift(profile, status)).To(BeNil())
+	})
+
+	It("should report drift when a node has fewer pages allocated than requested", func() {
+		status := v2.HugepagesStatus{
+			Nodes: []v2.NodeHugepagesStatus{
+				{NodeName: "node0", Sizes: []v2.HugePageSizeStatus{{Size: "1G", Total: 2}}},
+			},
+		}
+		condition := DetectDrift(profile, status)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(ConditionDegradedReasonDrift))
+	})
+})
@@ -0,0 +1,97 @@
+package hugepagesstatus
+
+import (
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ComponentName is the name shared by the DaemonSet, its Pods and
+	// ServiceAccount.
+	ComponentName = "hugepages-status-agent"
+
+	containerName = "hugepages-status-agent"
+)
+
+// NewDaemonSet returns the DaemonSet that runs the hugepages-status-agent on
+// every node matched by the profile's NodeSelector. The agent reads the
+// node's hugepage state out of sysfs (procfs is mounted as well but not
+// read) and annotates the Node object with the result, which the performanceprofile controller later
+// aggregates onto PerformanceProfile.Status.HugepagesStatus.
+func NewDaemonSet(profile *v2.PerformanceProfile, image string) *appsv1.DaemonSet {
+	hostPathDirectory := corev1.HostPathDirectory
+
+	labels := map[string]string{
+		"name": ComponentName,
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ComponentName,
+			Namespace: components.NamespaceNodeFunction,
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector:       profile.Spec.NodeSelector,
+					ServiceAccountName: ComponentName,
+					HostPID:            true,
+					Containers: []corev1.Container{
+						{
+							Name:    containerName,
+							Image:   image,
+							Command: []string{"/usr/bin/hugepages-status-agent"},
+							Env: []corev1.EnvVar{
+								{
+									Name: "NODE_NAME",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "spec.nodeName",
+										},
+									},
+								},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: boolPtr(false),
+								ReadOnlyRootFilesystem: boolPtr(true),
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "sys", MountPath: "/host-sys", ReadOnly: true},
+								{Name: "proc", MountPath: "/host-proc", ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "sys",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{Path: "/sys", Type: &hostPathDirectory},
+							},
+						},
+						{
+							Name: "proc",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{Path: "/proc", Type: &hostPathDirectory},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,156 @@
+package hugepagesstatus
+
+import (
+	"context"
+	"fmt"
+
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// reconciler keeps the hugepages-status-agent DaemonSet and
+// PerformanceProfile.Status.HugepagesStatus in sync for every
+// PerformanceProfile in the cluster.
+type reconciler struct {
+	client     client.Client
+	scheme     *runtime.Scheme
+	agentImage string
+}
+
+// AddToManager registers the hugepagesstatus controller with mgr. It watches
+// PerformanceProfile objects and, on every reconcile, ensures the
+// hugepages-status-agent DaemonSet exists and refreshes
+// PerformanceProfile.Status.HugepagesStatus from the nodes the profile
+// matches. It also watches Node objects directly, since the agent reports
+// new data by annotating a Node rather than touching the PerformanceProfile
+// itself, and Status.HugepagesStatus should reflect that promptly rather
+// than waiting for the profile's next resync.
+func AddToManager(mgr manager.Manager, agentImage string) error {
+	r := &reconciler{client: mgr.GetClient(), scheme: mgr.GetScheme(), agentImage: agentImage}
+
+	c, err := controller.New("hugepagesstatus-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return fmt.Errorf("failed to create hugepagesstatus controller: %v", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &v2.PerformanceProfile{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to watch PerformanceProfile: %v", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &corev1.Node{}}, handler.EnqueueRequestsFromMapFunc(r.profilesMatchingNode)); err != nil {
+		return fmt.Errorf("failed to watch Node: %v", err)
+	}
+
+	return nil
+}
+
+// profilesMatchingNode maps a Node event to a reconcile.Request for every
+// PerformanceProfile whose NodeSelector matches it, so a fresh
+// hugepages-status-agent annotation on the node is picked up without
+// waiting for the owning profile to change or resync.
+func (r *reconciler) profilesMatchingNode(obj client.Object) []reconcile.Request {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return nil
+	}
+
+	var profileList v2.PerformanceProfileList
+	if err := r.client.List(context.Background(), &profileList); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range profileList.Items {
+		profile := &profileList.Items[i]
+		if labels.SelectorFromSet(profile.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: profile.Name, Namespace: profile.Namespace},
+			})
+		}
+	}
+
+	return requests
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	profile := &v2.PerformanceProfile{}
+	if err := r.client.Get(ctx, req.NamespacedName, profile); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if err := r.reconcileProfile(ctx, profile); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := r.client.Status().Update(ctx, profile); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update status of profile %q: %v", profile.Name, err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// reconcileProfile ensures the DaemonSet exists for profile and refreshes
+// profile.Status in place; the caller is responsible for persisting it.
+func (r *reconciler) reconcileProfile(ctx context.Context, profile *v2.PerformanceProfile) error {
+	if err := r.ensureDaemonSet(ctx, profile); err != nil {
+		return err
+	}
+
+	var nodeList corev1.NodeList
+	if err := r.client.List(ctx, &nodeList, client.MatchingLabels(profile.Spec.NodeSelector)); err != nil {
+		return fmt.Errorf("failed to list nodes matching NodeSelector %v: %v", profile.Spec.NodeSelector, err)
+	}
+
+	nodes := make([]*corev1.Node, len(nodeList.Items))
+	for i := range nodeList.Items {
+		nodes[i] = &nodeList.Items[i]
+	}
+
+	status, err := AggregateStatus(nodes)
+	if err != nil {
+		return err
+	}
+	profile.Status.HugepagesStatus = &status
+
+	if condition := DetectDrift(profile, status); condition != nil {
+		conditionsv1.SetStatusCondition(&profile.Status.Conditions, *condition)
+	}
+
+	return nil
+}
+
+func (r *reconciler) ensureDaemonSet(ctx context.Context, profile *v2.PerformanceProfile) error {
+	desired := NewDaemonSet(profile, r.agentImage)
+	if err := controllerutil.SetControllerReference(profile, desired, r.scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference on DaemonSet %s/%s: %v", desired.Namespace, desired.Name, err)
+	}
+
+	existing := &appsv1.DaemonSet{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if apierrors.IsNotFound(err) {
+		return r.client.Create(ctx, desired)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get DaemonSet %s/%s: %v", desired.Namespace, desired.Name, err)
+	}
+
+	existing.Spec = desired.Spec
+	return r.client.Update(ctx, existing)
+}
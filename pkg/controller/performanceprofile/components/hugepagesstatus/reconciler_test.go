@@ -0,0 +1,104 @@
+package hugepagesstatus
+
+import (
+	"context"
+	"encoding/json"
+
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("reconcileProfile", func() {
+
+	var (
+		profile *v2.PerformanceProfile
+		node    *corev1.Node
+	)
+
+	BeforeEach(func() {
+		profile = &v2.PerformanceProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: v2.PerformanceProfileSpec{
+				NodeSelector: map[string]string{"fooDomain/barRole": ""},
+				HugePages: &v2.HugePages{
+					Pages: []v2.HugePage{{Size: "1G", Count: 4}},
+				},
+			},
+		}
+
+		observed, err := json.Marshal(v2.NodeHugepagesStatus{
+			Sizes: []v2.HugePageSizeStatus{{Size: "1G", Total: 4}},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		node = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "node0",
+				Labels:      map[string]string{"fooDomain/barRole": ""},
+				Annotations: map[string]string{StatusAnnotationKey: string(observed)},
+			},
+		}
+	})
+
+	It("should create the DaemonSet and populate HugepagesStatus on first reconcile", func() {
+		scheme := newTestScheme()
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+		r := &reconciler{client: fakeClient, scheme: scheme, agentImage: "quay.io/example/hugepages-status-agent:test"}
+
+		Expect(r.reconcileProfile(context.TODO(), profile)).To(Succeed())
+
+		Expect(profile.Status.HugepagesStatus).ToNot(BeNil())
+		Expect(profile.Status.HugepagesStatus.Nodes).To(HaveLen(1))
+
+		expected := NewDaemonSet(profile, "quay.io/example/hugepages-status-agent:test")
+		ds := &appsv1.DaemonSet{}
+		key := types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}
+		Expect(fakeClient.Get(context.TODO(), key, ds)).To(Succeed())
+
+		Expect(ds.OwnerReferences).To(HaveLen(1))
+		Expect(ds.OwnerReferences[0].Name).To(Equal(profile.Name))
+	})
+
+	It("should raise a drift condition when a node under-reports its allocation", func() {
+		node.Annotations[StatusAnnotationKey] = mustMarshalNodeStatus(v2.NodeHugepagesStatus{
+			Sizes: []v2.HugePageSizeStatus{{Size: "1G", Total: 1}},
+		})
+
+		scheme := newTestScheme()
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+		r := &reconciler{client: fakeClient, scheme: scheme, agentImage: "quay.io/example/hugepages-status-agent:test"}
+
+		Expect(r.reconcileProfile(context.TODO(), profile)).To(Succeed())
+
+		found := false
+		for _, c := range profile.Status.Conditions {
+			if c.Reason == ConditionDegradedReasonDrift {
+				found = true
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+})
+
+func mustMarshalNodeStatus(status v2.NodeHugepagesStatus) string {
+	raw, err := json.Marshal(status)
+	Expect(err).ShouldNot(HaveOccurred())
+	return string(raw)
+}
+
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+	Expect(v2.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
@@ -0,0 +1,451 @@
+package profile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisterv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog"
+)
+
+const (
+	hugepagesSize1G = v2.HugePageSize("1G")
+	hugepagesSize2M = v2.HugePageSize("2M")
+
+	// archX86_64, archArm64 and archPPC64le are the canonical names we key
+	// the hugepage size registry with; x86_64 is the `uname -m` name rather
+	// than the `kubernetes.io/arch` node label value (the GOARCH value
+	// "amd64"), see nodeArchToRegistryKey.
+	archX86_64  = "x86_64"
+	archArm64   = "arm64"
+	archPPC64le = "ppc64le"
+)
+
+// archHugePageSizes lists, per CPU architecture, the hugepage sizes the
+// kernel supports on that architecture.
+var archHugePageSizes = map[string][]v2.HugePageSize{
+	archX86_64:  {hugepagesSize2M, hugepagesSize1G},
+	archArm64:   {"64Ki", "2Mi", "32Mi", "512Mi", "16Gi"},
+	archPPC64le: {"2Mi", "1Gi", "16Mi", "16Gi"},
+}
+
+// nodeArchToRegistryKey translates the value of the kubernetes.io/arch
+// node label (a GOARCH string) into the key used by archHugePageSizes.
+func nodeArchToRegistryKey(nodeArch string) string {
+	switch nodeArch {
+	case "amd64":
+		return archX86_64
+	case "arm64":
+		return archArm64
+	case "ppc64le":
+		return archPPC64le
+	default:
+		return nodeArch
+	}
+}
+
+// AllowedHugePageSizesForArch returns the hugepage sizes the kernel supports
+// on the given CPU architecture (keyed the same way as the
+// kubernetes.io/arch node label, e.g. "amd64", "arm64", "ppc64le"), or false
+// if the architecture is not registered.
+func AllowedHugePageSizesForArch(nodeArch string) ([]v2.HugePageSize, bool) {
+	sizes, ok := archHugePageSizes[nodeArchToRegistryKey(nodeArch)]
+	return sizes, ok
+}
+
+// knownHugePageSizes is the union of every architecture's allowed hugepage
+// sizes. ValidateParameters runs without knowledge of which architecture the
+// profile will land on, so offline it can only reject sizes no supported
+// architecture recognizes; ValidateParametersAgainstCluster is what enforces
+// that a size is valid for the architecture actually matched.
+func knownHugePageSizes() []v2.HugePageSize {
+	seen := map[v2.HugePageSize]bool{}
+	var sizes []v2.HugePageSize
+	for _, archSizes := range archHugePageSizes {
+		for _, size := range archSizes {
+			if !seen[size] {
+				seen[size] = true
+				sizes = append(sizes, size)
+			}
+		}
+	}
+	return sizes
+}
+
+// ValidateParameters validates the performance profile parameters that can be
+// checked offline, i.e. without talking to the API server or any node in the
+// cluster. See ValidateParametersAgainstCluster for the cluster-aware checks.
+func ValidateParameters(profile *v2.PerformanceProfile) error {
+	if err := validateCPU(profile); err != nil {
+		return err
+	}
+
+	if err := validateSelectors(profile); err != nil {
+		return err
+	}
+
+	if err := validateHugePages(profile); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateParametersAgainstCluster composes the offline ValidateParameters
+// checks with validation that requires knowledge of the nodes the profile's
+// NodeSelector actually matches. It is meant to be called from the
+// PerformanceProfile admission webhook, using a cached NodeLister so that no
+// API server round-trip is needed per admission request.
+//
+// A NodeSelector that currently matches zero nodes is accepted (nodes may
+// join the cluster later); in that case the architecture and CPU-existence
+// checks below are simply skipped.
+func ValidateParametersAgainstCluster(profile *v2.PerformanceProfile, nodeLister corelisterv1.NodeLister) error {
+	if err := ValidateParameters(profile); err != nil {
+		return err
+	}
+
+	nodes, err := nodeLister.List(labels.SelectorFromSet(profile.Spec.NodeSelector))
+	if err != nil {
+		return fmt.Errorf("failed to list nodes matching NodeSelector %v: %v", profile.Spec.NodeSelector, err)
+	}
+
+	if len(nodes) == 0 {
+		klog.Warningf("performance profile %q NodeSelector %v matches no nodes yet; skipping architecture and CPU-existence checks", profile.Name, profile.Spec.NodeSelector)
+		return nil
+	}
+
+	arch, err := validateHomogeneousArch(nodes)
+	if err != nil {
+		return err
+	}
+
+	if err := validateCPUsExistOnNodes(profile, nodes); err != nil {
+		return err
+	}
+
+	if err := validateHugePagesSupportedOnArch(profile, arch); err != nil {
+		return err
+	}
+
+	if err := validateNUMATopology(profile, nodes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateHomogeneousArch(nodes []*corev1.Node) (string, error) {
+	arch := nodes[0].Labels[corev1.LabelArchStable]
+	for _, node := range nodes[1:] {
+		nodeArch := node.Labels[corev1.LabelArchStable]
+		if nodeArch != arch {
+			return "", fmt.Errorf("nodes matched by NodeSelector report mixed %q labels (%q on %q, %q on %q); a single profile cannot target mixed architectures",
+				corev1.LabelArchStable, arch, nodes[0].Name, nodeArch, node.Name)
+		}
+	}
+	return arch, nil
+}
+
+func validateCPUsExistOnNodes(profile *v2.PerformanceProfile, nodes []*corev1.Node) error {
+	maxCPUID, err := maxRequestedCPUID(profile)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		capacity, ok := node.Status.Capacity[corev1.ResourceCPU]
+		if !ok {
+			return fmt.Errorf("node %q reports no cpu capacity", node.Name)
+		}
+
+		if cpuCount := capacity.Value(); int64(maxCPUID) >= cpuCount {
+			return fmt.Errorf("node %q has %d logical CPUs, but the profile requests CPU id %d", node.Name, cpuCount, maxCPUID)
+		}
+	}
+
+	return nil
+}
+
+func maxRequestedCPUID(profile *v2.PerformanceProfile) (int, error) {
+	max := -1
+	for _, cpus := range []*v2.CPUSet{profile.Spec.CPU.Isolated, profile.Spec.CPU.Reserved} {
+		if cpus == nil {
+			continue
+		}
+
+		ids, err := parseCPUSet(*cpus)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, id := range ids {
+			if id > max {
+				max = id
+			}
+		}
+	}
+	return max, nil
+}
+
+// parseCPUSet parses a Linux cpuset list, e.g. "0-3,8,10-11", into the
+// individual CPU ids it contains.
+func parseCPUSet(cpus v2.CPUSet) ([]int, error) {
+	var ids []int
+
+	for _, group := range strings.Split(string(cpus), ",") {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(group, "-", 2)
+		start, err := parseCPUID(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cpuset %q: %v", cpus, err)
+		}
+
+		end := start
+		if len(bounds) == 2 {
+			end, err = parseCPUID(bounds[1])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse cpuset %q: %v", cpus, err)
+			}
+		}
+
+		for id := start; id <= end; id++ {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+func parseCPUID(s string) (int, error) {
+	var id int
+	if _, err := fmt.Sscanf(s, "%d", &id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func validateHugePagesSupportedOnArch(profile *v2.PerformanceProfile, nodeArch string) error {
+	if profile.Spec.HugePages == nil {
+		return nil
+	}
+
+	allowed, ok := archHugePageSizes[nodeArchToRegistryKey(nodeArch)]
+	if !ok {
+		return fmt.Errorf("hugepages are not supported for architecture %q", nodeArch)
+	}
+
+	for _, page := range profile.Spec.HugePages.Pages {
+		if !hugePageSizeAllowed(page.Size, allowed) {
+			return fmt.Errorf("the page size %q is not supported on architecture %q", page.Size, nodeArch)
+		}
+	}
+
+	return nil
+}
+
+func hugePageSizeAllowed(size v2.HugePageSize, allowed []v2.HugePageSize) bool {
+	for _, a := range allowed {
+		if a == size {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyBinarySizeSuffixes are the bare "G"/"M"/"K" suffixes historically
+// used for x86_64 hugepage sizes (see hugepagesSize1G, hugepagesSize2M, and
+// kbToHugePageSize in cmd/hugepages-status-agent). They mean binary
+// (1024-based) units here, unlike resource.ParseQuantity, which treats a
+// bare "G"/"M" as decimal SI and does not accept "K". The explicit "Gi"/"Mi"/"Ki" suffixes used
+// by the arm64/ppc64le registry entries already mean binary to
+// resource.ParseQuantity, so those are left to it below.
+var legacyBinarySizeSuffixes = map[string]int64{
+	"K": 1024,
+	"M": 1024 * 1024,
+	"G": 1024 * 1024 * 1024,
+}
+
+// hugePageSizeBytes parses a HugePageSize (e.g. "2M", "1Gi") into the number
+// of bytes it represents.
+func hugePageSizeBytes(size v2.HugePageSize) (int64, error) {
+	s := string(size)
+
+	for suffix, unitBytes := range legacyBinarySizeSuffixes {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+		count, err := strconv.ParseInt(strings.TrimSuffix(s, suffix), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse hugepage size %q: %v", size, err)
+		}
+		return count * unitBytes, nil
+	}
+
+	quantity, err := resource.ParseQuantity(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse hugepage size %q: %v", size, err)
+	}
+	return quantity.Value(), nil
+}
+
+// basePageSizeBytes is the smallest unit of memory the kernel's page
+// allocator deals in on every architecture we support; any hugepage size
+// must be an integer multiple of it.
+const basePageSizeBytes = 4096
+
+// validateHugePageDivisibility mirrors upstream Kubernetes' "indivisible
+// hugepage value" check. A profile must not ask for zero or a negative
+// number of pages, and the size itself must land on a base-page boundary —
+// a size like "3Ki" can never be honored by the kernel's hugepage allocator.
+func validateHugePageDivisibility(page v2.HugePage) error {
+	if page.Count <= 0 {
+		return fmt.Errorf("the page count for size %q should be a positive number of pages", page.Size)
+	}
+
+	sizeBytes, err := hugePageSizeBytes(page.Size)
+	if err != nil {
+		return err
+	}
+
+	if sizeBytes%basePageSizeBytes != 0 {
+		return fmt.Errorf("the hugepage size %q (%d bytes) is not divisible by the base page size (%d bytes)", page.Size, sizeBytes, basePageSizeBytes)
+	}
+
+	return nil
+}
+
+func validateCPU(profile *v2.PerformanceProfile) error {
+	if profile.Spec.CPU == nil {
+		return fmt.Errorf("you should provide CPU section")
+	}
+
+	if profile.Spec.CPU.Isolated == nil {
+		return fmt.Errorf("you should provide isolated CPU set")
+	}
+
+	return nil
+}
+
+func validateSelectors(profile *v2.PerformanceProfile) error {
+	if len(profile.Spec.MachineConfigLabel) > 1 {
+		return fmt.Errorf("you should provide only 1 MachineConfigLabel")
+	}
+
+	if len(profile.Spec.MachineConfigPoolSelector) > 1 {
+		return fmt.Errorf("you should provide only 1 MachineConfigPoolSelector")
+	}
+
+	if profile.Spec.MachineConfigLabel == nil || profile.Spec.MachineConfigPoolSelector == nil {
+		if _, err := machineConfigRoleFromNodeSelector(profile); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// machineConfigRoleFromNodeSelector derives the MachineConfig role to use
+// when MachineConfigLabel or MachineConfigPoolSelector were not provided
+// explicitly, by inspecting the profile's NodeSelector. The NodeSelector is
+// expected to carry exactly one key of the form "<domain>/<role>".
+func machineConfigRoleFromNodeSelector(profile *v2.PerformanceProfile) (string, error) {
+	if len(profile.Spec.NodeSelector) != 1 {
+		return "", fmt.Errorf("you should provide a single NodeSelector, or explicit MachineConfigLabel and MachineConfigPoolSelector")
+	}
+
+	for k := range profile.Spec.NodeSelector {
+		parts := strings.SplitN(k, "/", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return "", fmt.Errorf("the NodeSelector key %q does not have the expected <domain>/<role> format", k)
+		}
+		return parts[1], nil
+	}
+
+	return "", fmt.Errorf("unreachable")
+}
+
+func validateHugePages(profile *v2.PerformanceProfile) error {
+	if profile.Spec.HugePages == nil {
+		return nil
+	}
+
+	if profile.Spec.HugePages.DefaultHugePagesSize != nil {
+		defaultSize := *profile.Spec.HugePages.DefaultHugePagesSize
+		if !hugePageSizeAllowed(defaultSize, knownHugePageSizes()) {
+			return fmt.Errorf("hugepages default size %q is not a recognized hugepage size", defaultSize)
+		}
+	}
+
+	perSizeWithNode := map[v2.HugePageSize]map[int32]bool{}
+	perSizeWithoutNode := map[v2.HugePageSize]bool{}
+
+	for _, page := range profile.Spec.HugePages.Pages {
+		if !hugePageSizeAllowed(page.Size, knownHugePageSizes()) {
+			return fmt.Errorf("the page size %q is not a recognized hugepage size", page.Size)
+		}
+
+		if err := validateHugePageDivisibility(page); err != nil {
+			return err
+		}
+
+		if page.Node == nil {
+			if perSizeWithoutNode[page.Size] {
+				return fmt.Errorf("the page with the size %q and without the specified NUMA node, has duplication", page.Size)
+			}
+			perSizeWithoutNode[page.Size] = true
+			continue
+		}
+
+		if perSizeWithNode[page.Size] == nil {
+			perSizeWithNode[page.Size] = map[int32]bool{}
+		}
+		if perSizeWithNode[page.Size][*page.Node] {
+			return fmt.Errorf("the page with the size %q and with specified NUMA node %d, has duplication", page.Size, *page.Node)
+		}
+		perSizeWithNode[page.Size][*page.Node] = true
+	}
+
+	for size := range perSizeWithNode {
+		if perSizeWithoutNode[size] {
+			return fmt.Errorf("the page with the size %q is requested both per-NUMA-node and without a NUMA node; the kernel cannot honor both at once for the same size", size)
+		}
+	}
+
+	return nil
+}
+
+// GetMachineConfigLabel returns the MachineConfigLabel to apply, falling
+// back to a label derived from the profile's NodeSelector when none was
+// given explicitly.
+func GetMachineConfigLabel(profile *v2.PerformanceProfile) map[string]string {
+	if profile.Spec.MachineConfigLabel != nil {
+		return profile.Spec.MachineConfigLabel
+	}
+
+	role, _ := machineConfigRoleFromNodeSelector(profile)
+	return map[string]string{components.MachineConfigRoleLabelKey: role}
+}
+
+// GetMachineConfigPoolSelector returns the MachineConfigPoolSelector to
+// apply, falling back to a label derived from the profile's NodeSelector
+// when none was given explicitly.
+func GetMachineConfigPoolSelector(profile *v2.PerformanceProfile) map[string]string {
+	if profile.Spec.MachineConfigPoolSelector != nil {
+		return profile.Spec.MachineConfigPoolSelector
+	}
+
+	role, _ := machineConfigRoleFromNodeSelector(profile)
+	return map[string]string{components.MachineConfigRoleLabelKey: role}
+}
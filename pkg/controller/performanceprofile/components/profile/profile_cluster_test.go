@@ -0,0 +1,89 @@
+package profile
+
+import (
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisterv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+func newNode(name, arch string, cpuCount int64) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				corev1.LabelArchStable:         arch,
+				"fooDomain/" + NodeSelectorRole: "",
+			},
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU: *resource.NewQuantity(cpuCount, resource.DecimalSI),
+			},
+		},
+	}
+}
+
+func newNodeLister(nodes ...*corev1.Node) corelisterv1.NodeLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range nodes {
+		_ = indexer.Add(node)
+	}
+	return corelisterv1.NewNodeLister(indexer)
+}
+
+func cpuSetPtr(s string) *v2.CPUSet {
+	cpus := v2.CPUSet(s)
+	return &cpus
+}
+
+var _ = Describe("PerformanceProfile cluster-aware validation", func() {
+
+	var profile *v2.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		setValidNodeSelector(profile)
+	})
+
+	It("should pass the happy path with homogeneous, sufficiently sized nodes", func() {
+		lister := newNodeLister(
+			newNode("node0", "amd64", 8),
+			newNode("node1", "amd64", 8),
+		)
+		Expect(ValidateParametersAgainstCluster(profile, lister)).ShouldNot(HaveOccurred())
+	})
+
+	It("should reject a NodeSelector matching nodes with mixed architectures", func() {
+		lister := newNodeLister(
+			newNode("node0", "amd64", 8),
+			newNode("node1", "arm64", 8),
+		)
+		err := ValidateParametersAgainstCluster(profile, lister)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("mixed"))
+	})
+
+	It("should reject isolated CPU ids that do not exist on the matched nodes", func() {
+		profile.Spec.CPU.Isolated = cpuSetPtr("8-9")
+		lister := newNodeLister(
+			newNode("node0", "amd64", 4),
+		)
+		err := ValidateParametersAgainstCluster(profile, lister)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("logical CPUs"))
+	})
+
+	It("should allow a NodeSelector matching zero nodes", func() {
+		lister := newNodeLister()
+		Expect(ValidateParametersAgainstCluster(profile, lister)).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,84 @@
+package profile
+
+import (
+	"fmt"
+
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+var _ = Describe("Hugepage size registry", func() {
+
+	DescribeTable("should report the sizes supported by each architecture",
+		func(nodeArch string, expected []v2.HugePageSize) {
+			sizes, ok := AllowedHugePageSizesForArch(nodeArch)
+			Expect(ok).To(BeTrue())
+			Expect(sizes).To(ConsistOf(expected))
+		},
+		Entry("amd64", "amd64", []v2.HugePageSize{hugepagesSize2M, hugepagesSize1G}),
+		Entry("arm64", "arm64", []v2.HugePageSize{v2.HugePageSize("64Ki"), v2.HugePageSize("2Mi"), v2.HugePageSize("32Mi"), v2.HugePageSize("512Mi"), v2.HugePageSize("16Gi")}),
+		Entry("ppc64le", "ppc64le", []v2.HugePageSize{v2.HugePageSize("2Mi"), v2.HugePageSize("1Gi"), v2.HugePageSize("16Mi"), v2.HugePageSize("16Gi")}),
+	)
+
+	It("should report unknown architectures as unsupported", func() {
+		_, ok := AllowedHugePageSizesForArch("s390x")
+		Expect(ok).To(BeFalse())
+	})
+
+	DescribeTable("should reject a size unsupported for an architecture when cross-checked against a cluster node",
+		func(nodeArch string, size v2.HugePageSize) {
+			profile := testutils.NewPerformanceProfile("test")
+			setValidNodeSelector(profile)
+			profile.Spec.HugePages.Pages = []v2.HugePage{
+				{Count: 4, Size: size},
+			}
+
+			lister := newNodeLister(newNode("node0", nodeArch, 8))
+			err := ValidateParametersAgainstCluster(profile, lister)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("not supported on architecture %q", nodeArch)))
+		},
+		Entry("a ppc64le-only size requested on an arm64 node", "arm64", v2.HugePageSize("16Mi")),
+		Entry("an arm64-only size requested on a ppc64le node", "ppc64le", v2.HugePageSize("64Ki")),
+	)
+})
+
+var _ = Describe("Hugepage count divisibility", func() {
+
+	var profile *v2.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+	})
+
+	DescribeTable("should reject a non-positive page count",
+		func(count int32) {
+			profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v2.HugePage{
+				Count: count,
+				Size:  hugepagesSize2M,
+				Node:  pointer.Int32Ptr(0),
+			})
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("should be a positive number of pages"))
+		},
+		Entry("zero pages", int32(0)),
+		Entry("negative pages", int32(-1)),
+	)
+
+	It("should reject a hugepage size that is not a multiple of the base page size", func() {
+		err := validateHugePageDivisibility(v2.HugePage{Count: 1, Size: v2.HugePageSize("3Ki")})
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("not divisible by the base page size"))
+	})
+
+	It("should accept a hugepage size that is a multiple of the base page size", func() {
+		Expect(validateHugePageDivisibility(v2.HugePage{Count: 1, Size: hugepagesSize2M})).ShouldNot(HaveOccurred())
+	})
+})
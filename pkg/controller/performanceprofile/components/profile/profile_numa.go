@@ -0,0 +1,84 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/hugepagesstatus"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// validateNUMATopology checks, for every HugePage entry that pins pages to a
+// specific NUMA node, that the node index actually exists on every node
+// matched by the profile, and that the requested allocation fits within that
+// NUMA node's memory capacity.
+//
+// The NUMA topology (which node indices exist, and their memory capacity) is
+// read from the same hugepages-status-agent annotation the hugepagesstatus
+// subsystem uses to populate PerformanceProfile.Status; a node that has not
+// reported yet is skipped rather than treated as an error, mirroring how
+// ValidateParametersAgainstCluster accepts a NodeSelector that matches no
+// nodes.
+func validateNUMATopology(profile *v2.PerformanceProfile, nodes []*corev1.Node) error {
+	if profile.Spec.HugePages == nil {
+		return nil
+	}
+
+	perNUMARequests := map[int32]map[v2.HugePageSize]int64{}
+	for _, page := range profile.Spec.HugePages.Pages {
+		if page.Node == nil {
+			continue
+		}
+
+		sizeBytes, err := hugePageSizeBytes(page.Size)
+		if err != nil {
+			return err
+		}
+
+		if perNUMARequests[*page.Node] == nil {
+			perNUMARequests[*page.Node] = map[v2.HugePageSize]int64{}
+		}
+		perNUMARequests[*page.Node][page.Size] += int64(page.Count) * sizeBytes
+	}
+
+	if len(perNUMARequests) == 0 {
+		return nil
+	}
+
+	for _, node := range nodes {
+		raw, ok := node.Annotations[hugepagesstatus.StatusAnnotationKey]
+		if !ok {
+			continue
+		}
+
+		var topology v2.NodeHugepagesStatus
+		if err := json.Unmarshal([]byte(raw), &topology); err != nil {
+			return fmt.Errorf("failed to parse %q annotation on node %q: %v", hugepagesstatus.StatusAnnotationKey, node.Name, err)
+		}
+
+		memTotalKB := map[int32]int64{}
+		for _, numa := range topology.NUMA {
+			memTotalKB[numa.NUMANodeID] = numa.MemTotalKB
+		}
+
+		for numaNode, requested := range perNUMARequests {
+			capacityKB, exists := memTotalKB[numaNode]
+			if !exists {
+				return fmt.Errorf("node %q has no NUMA node %d", node.Name, numaNode)
+			}
+
+			var requestedTotal int64
+			for _, bytes := range requested {
+				requestedTotal += bytes
+			}
+
+			if capacityBytes := capacityKB * 1024; requestedTotal > capacityBytes {
+				return fmt.Errorf("node %q NUMA node %d has %d bytes of memory, but the profile requests %d bytes of hugepages on it", node.Name, numaNode, capacityBytes, requestedTotal)
+			}
+		}
+	}
+
+	return nil
+}
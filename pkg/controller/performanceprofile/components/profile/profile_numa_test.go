@@ -0,0 +1,126 @@
+package profile
+
+import (
+	"encoding/json"
+
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/hugepagesstatus"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+func nodeWithTopology(name, arch string, topology v2.NodeHugepagesStatus) *corev1.Node {
+	node := newNode(name, arch, 8)
+
+	raw, err := json.Marshal(topology)
+	Expect(err).ShouldNot(HaveOccurred())
+	node.Annotations = map[string]string{
+		hugepagesstatus.StatusAnnotationKey: string(raw),
+	}
+
+	return node
+}
+
+var _ = Describe("PerformanceProfile NUMA-aware validation", func() {
+
+	var profile *v2.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		setValidNodeSelector(profile)
+		profile.Spec.HugePages.Pages = nil
+	})
+
+	It("should reject a HugePage pinned to a NUMA node that does not exist on a matched node", func() {
+		profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v2.HugePage{
+			Count: 4,
+			Size:  hugepagesSize1G,
+			Node:  pointer.Int32Ptr(7),
+		})
+
+		node := nodeWithTopology("node0", "amd64", v2.NodeHugepagesStatus{
+			NUMA: []v2.NUMAHugepagesStatus{
+				{NUMANodeID: 0, MemTotalKB: 16314368},
+			},
+		})
+
+		err := ValidateParametersAgainstCluster(profile, newNodeLister(node))
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("has no NUMA node 7"))
+	})
+
+	It("should reject a per-NUMA allocation that exceeds the NUMA node's memory capacity", func() {
+		profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v2.HugePage{
+			Count: 64,
+			Size:  hugepagesSize1G,
+			Node:  pointer.Int32Ptr(0),
+		})
+
+		node := nodeWithTopology("node0", "amd64", v2.NodeHugepagesStatus{
+			NUMA: []v2.NUMAHugepagesStatus{
+				{NUMANodeID: 0, MemTotalKB: 16314368},
+			},
+		})
+
+		err := ValidateParametersAgainstCluster(profile, newNodeLister(node))
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("requests"))
+		Expect(err.Error()).To(ContainSubstring("bytes of hugepages"))
+	})
+
+	It("should accept a per-NUMA allocation that fits within capacity", func() {
+		profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v2.HugePage{
+			Count: 2,
+			Size:  hugepagesSize1G,
+			Node:  pointer.Int32Ptr(0),
+		})
+
+		node := nodeWithTopology("node0", "amd64", v2.NodeHugepagesStatus{
+			NUMA: []v2.NUMAHugepagesStatus{
+				{NUMANodeID: 0, MemTotalKB: 16314368},
+			},
+		})
+
+		Expect(ValidateParametersAgainstCluster(profile, newNodeLister(node))).ShouldNot(HaveOccurred())
+	})
+
+	It("should reject a per-NUMA allocation that only fits if 1G is miscounted as decimal instead of binary bytes", func() {
+		// 2 pages of "1G" is 2 GiB = 2,147,483,648 bytes = 2,097,152 KB. A
+		// capacity of 2,000,000 KB (2,048,000,000 bytes) sits strictly between
+		// that and the wrong decimal-SI total (2 * 1,000,000,000 = 2,000,000,000
+		// bytes): the request only "fits" if 1G is read as 10^9 bytes instead of
+		// 2^30, so this boundary catches a regression to the decimal math.
+		profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v2.HugePage{
+			Count: 2,
+			Size:  hugepagesSize1G,
+			Node:  pointer.Int32Ptr(0),
+		})
+
+		node := nodeWithTopology("node0", "amd64", v2.NodeHugepagesStatus{
+			NUMA: []v2.NUMAHugepagesStatus{
+				{NUMANodeID: 0, MemTotalKB: 2000000},
+			},
+		})
+
+		err := ValidateParametersAgainstCluster(profile, newNodeLister(node))
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("bytes of hugepages"))
+	})
+
+	It("should reject a size that is requested both per-NUMA-node and without a NUMA node", func() {
+		profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages,
+			v2.HugePage{Count: 4, Size: hugepagesSize1G},
+			v2.HugePage{Count: 4, Size: hugepagesSize1G, Node: pointer.Int32Ptr(0)},
+		)
+
+		err := ValidateParameters(profile)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("requested both per-NUMA-node and without a NUMA node"))
+	})
+})
@@ -74,18 +74,20 @@ var _ = Describe("PerformanceProfile", func() {
 
 			err := ValidateParameters(profile)
 			Expect(err).Should(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("hugepages default size should be equal"))
+			Expect(err.Error()).To(ContainSubstring("hugepages default size"))
+			Expect(err.Error()).To(ContainSubstring("not a recognized hugepage size"))
 		})
 
 		It("should reject hugepages allocation with unexpected page size", func() {
+			unexpectedSize := v2.HugePageSize("14M")
 			profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v2.HugePage{
 				Count: 128,
 				Node:  pointer.Int32Ptr(0),
-				Size:  v2.HugePageSize("14M"),
+				Size:  unexpectedSize,
 			})
 			err := ValidateParameters(profile)
 			Expect(err).Should(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the page size should be equal to %q or %q", hugepagesSize1G, hugepagesSize2M)))
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the page size %q is not a recognized hugepage size", unexpectedSize)))
 		})
 
 		When("pages have duplication", func() {
@@ -134,6 +136,42 @@ var _ = Describe("PerformanceProfile", func() {
 					Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the page with the size %q and without the specified NUMA node, has duplication", hugepagesSize1G)))
 				})
 			})
+
+			Context("with an architecture-native size and specified NUMA node", func() {
+				It("should raise the validation error", func() {
+					nativeSize := v2.HugePageSize("2Mi")
+					profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v2.HugePage{
+						Count: 128,
+						Size:  nativeSize,
+						Node:  pointer.Int32Ptr(1),
+					})
+					profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v2.HugePage{
+						Count: 64,
+						Size:  nativeSize,
+						Node:  pointer.Int32Ptr(1),
+					})
+					err := ValidateParameters(profile)
+					Expect(err).Should(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the page with the size %q and with specified NUMA node 1, has duplication", nativeSize)))
+				})
+			})
+
+			Context("with an architecture-native size and without specified NUMA node", func() {
+				It("should raise the validation error", func() {
+					nativeSize := v2.HugePageSize("16Mi")
+					profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v2.HugePage{
+						Count: 128,
+						Size:  nativeSize,
+					})
+					profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v2.HugePage{
+						Count: 64,
+						Size:  nativeSize,
+					})
+					err := ValidateParameters(profile)
+					Expect(err).Should(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the page with the size %q and without the specified NUMA node, has duplication", nativeSize)))
+				})
+			})
 		})
 	})
 
@@ -0,0 +1,217 @@
+// Package sysfs reads the kernel's hugepage accounting out of sysfs and
+// procfs. It has no Kubernetes dependencies so it can be unit tested off-node
+// against fixture directories that mimic the real /sys and /proc layout.
+package sysfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SizeStats is the raw counters the kernel maintains for one hugepage size,
+// read from the four files under a hugepages-<N>kB directory.
+type SizeStats struct {
+	// SizeKB is the hugepage size in kB, taken from the directory name.
+	SizeKB   int64
+	Total    int64
+	Free     int64
+	Surplus  int64
+	Reserved int64
+}
+
+var hugepagesDirRegexp = regexp.MustCompile(`^hugepages-(\d+)kB$`)
+
+// ReadGlobal reads the node-wide hugepage counters from
+// <sysRoot>/kernel/mm/hugepages/hugepages-<N>kB/*, keyed by size in kB.
+func ReadGlobal(sysRoot string) (map[int64]SizeStats, error) {
+	return readHugepagesDir(filepath.Join(sysRoot, "kernel", "mm", "hugepages"))
+}
+
+// ReadNUMA reads the per-NUMA-node hugepage counters from
+// <sysRoot>/devices/system/node/node<N>/hugepages/hugepages-<N>kB/*, keyed
+// by NUMA node id and then by size in kB.
+func ReadNUMA(sysRoot string) (map[int32]map[int64]SizeStats, error) {
+	nodeDirRoot := filepath.Join(sysRoot, "devices", "system", "node")
+
+	entries, err := ioutil.ReadDir(nodeDirRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %v", nodeDirRoot, err)
+	}
+
+	nodeRegexp := regexp.MustCompile(`^node(\d+)$`)
+	result := map[int32]map[int64]SizeStats{}
+
+	for _, entry := range entries {
+		matches := nodeRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		numaID, err := strconv.ParseInt(matches[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse NUMA node id from %q: %v", entry.Name(), err)
+		}
+
+		sizes, err := readHugepagesDir(filepath.Join(nodeDirRoot, entry.Name(), "hugepages"))
+		if err != nil {
+			return nil, err
+		}
+
+		result[int32(numaID)] = sizes
+	}
+
+	return result, nil
+}
+
+func readHugepagesDir(hugepagesRoot string) (map[int64]SizeStats, error) {
+	entries, err := ioutil.ReadDir(hugepagesRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %v", hugepagesRoot, err)
+	}
+
+	result := map[int64]SizeStats{}
+
+	for _, entry := range entries {
+		matches := hugepagesDirRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		sizeKB, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse hugepage size from %q: %v", entry.Name(), err)
+		}
+
+		dir := filepath.Join(hugepagesRoot, entry.Name())
+		stats := SizeStats{SizeKB: sizeKB}
+
+		if stats.Total, err = readIntFile(filepath.Join(dir, "nr_hugepages")); err != nil {
+			return nil, err
+		}
+		if stats.Free, err = readIntFile(filepath.Join(dir, "free_hugepages")); err != nil {
+			return nil, err
+		}
+		if stats.Surplus, err = readIntFile(filepath.Join(dir, "surplus_hugepages")); err != nil {
+			return nil, err
+		}
+		if stats.Reserved, err = readIntFile(filepath.Join(dir, "resv_hugepages")); err != nil {
+			return nil, err
+		}
+
+		result[sizeKB] = stats
+	}
+
+	return result, nil
+}
+
+var numaMeminfoLineRegexp = regexp.MustCompile(`^Node\s+(\d+)\s+MemTotal:\s+(\d+)\s+kB$`)
+
+// ReadNUMAMemTotal reads the total memory capacity, in kB, of every NUMA
+// node present under <sysRoot>/devices/system/node/node<N>/meminfo, keyed by
+// NUMA node id.
+func ReadNUMAMemTotal(sysRoot string) (map[int32]int64, error) {
+	nodeDirRoot := filepath.Join(sysRoot, "devices", "system", "node")
+
+	entries, err := ioutil.ReadDir(nodeDirRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %v", nodeDirRoot, err)
+	}
+
+	nodeRegexp := regexp.MustCompile(`^node(\d+)$`)
+	result := map[int32]int64{}
+
+	for _, entry := range entries {
+		matches := nodeRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		numaID, err := strconv.ParseInt(matches[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse NUMA node id from %q: %v", entry.Name(), err)
+		}
+
+		path := filepath.Join(nodeDirRoot, entry.Name(), "meminfo")
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %v", path, err)
+		}
+
+		for _, line := range strings.Split(string(raw), "\n") {
+			matches := numaMeminfoLineRegexp.FindStringSubmatch(strings.TrimSpace(line))
+			if matches == nil {
+				continue
+			}
+
+			memTotalKB, err := strconv.ParseInt(matches[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse MemTotal from %q: %v", path, err)
+			}
+
+			result[int32(numaID)] = memTotalKB
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// ReadMeminfo reads <procRoot>/meminfo and returns the subset of fields
+// relevant to hugepage accounting (HugePages_Total, HugePages_Free,
+// HugePages_Rsvd, HugePages_Surp, Hugepagesize, Hugetlb), keyed by field
+// name without the trailing colon.
+func ReadMeminfo(procRoot string) (map[string]int64, error) {
+	path := filepath.Join(procRoot, "meminfo")
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %v", path, err)
+	}
+
+	result := map[string]int64{}
+
+	for _, line := range strings.Split(string(raw), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		key := strings.TrimSuffix(fields[0], ":")
+		switch key {
+		case "HugePages_Total", "HugePages_Free", "HugePages_Rsvd", "HugePages_Surp", "Hugepagesize", "Hugetlb":
+		default:
+			continue
+		}
+
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse meminfo field %q: %v", key, err)
+		}
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+func readIntFile(path string) (int64, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %q: %v", path, err)
+	}
+
+	value, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %q as an integer: %v", path, err)
+	}
+
+	return value, nil
+}
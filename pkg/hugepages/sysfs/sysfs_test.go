@@ -0,0 +1,62 @@
+package sysfs
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSysfs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "sysfs Suite")
+}
+
+const testdataSysRoot = "testdata/sys"
+const testdataProcRoot = "testdata/proc"
+
+var _ = Describe("sysfs parser", func() {
+
+	It("should read the node-global hugepage counters", func() {
+		stats, err := ReadGlobal(testdataSysRoot)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(stats).To(HaveKey(int64(2048)))
+		Expect(stats[2048]).To(Equal(SizeStats{SizeKB: 2048, Total: 4, Free: 1, Surplus: 0, Reserved: 0}))
+
+		Expect(stats).To(HaveKey(int64(1048576)))
+		Expect(stats[1048576]).To(Equal(SizeStats{SizeKB: 1048576, Total: 2, Free: 2, Surplus: 0, Reserved: 0}))
+	})
+
+	It("should read the per-NUMA-node hugepage counters", func() {
+		stats, err := ReadNUMA(testdataSysRoot)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(stats).To(HaveLen(2))
+		Expect(stats[0][2048]).To(Equal(SizeStats{SizeKB: 2048, Total: 2, Free: 1, Surplus: 0, Reserved: 0}))
+		Expect(stats[1][2048]).To(Equal(SizeStats{SizeKB: 2048, Total: 2, Free: 0, Surplus: 0, Reserved: 0}))
+	})
+
+	It("should read the per-NUMA-node memory capacity", func() {
+		memTotal, err := ReadNUMAMemTotal(testdataSysRoot)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(memTotal).To(HaveLen(2))
+		Expect(memTotal[0]).To(Equal(int64(16314368)))
+		Expect(memTotal[1]).To(Equal(int64(16314368)))
+	})
+
+	It("should read the relevant fields out of /proc/meminfo", func() {
+		meminfo, err := ReadMeminfo(testdataProcRoot)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(meminfo["HugePages_Total"]).To(Equal(int64(6)))
+		Expect(meminfo["HugePages_Free"]).To(Equal(int64(3)))
+		Expect(meminfo["Hugepagesize"]).To(Equal(int64(2048)))
+	})
+
+	It("should error when the sysfs root does not exist", func() {
+		_, err := ReadGlobal("testdata/does-not-exist")
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,54 @@
+package performanceprofile
+
+import (
+	"context"
+	"net/http"
+
+	v2 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v2"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
+
+	corelisterv1 "k8s.io/client-go/listers/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-performance-v2-performanceprofile,mutating=false,failurePolicy=fail,groups=performance.openshift.io,resources=performanceprofiles,verbs=create;update,versions=v2,name=vperformanceprofile.kb.io
+
+// validator implements admission.Handler, validating PerformanceProfile
+// objects against the cluster state known to a cached NodeLister. Using a
+// lister (backed by the manager's shared informers) avoids an API server
+// round-trip on every admission request.
+type validator struct {
+	nodeLister corelisterv1.NodeLister
+	decoder    *admission.Decoder
+}
+
+// AddToManager registers the PerformanceProfile validating webhook with mgr.
+func AddToManager(mgr manager.Manager, nodeLister corelisterv1.NodeLister) error {
+	mgr.GetWebhookServer().Register(
+		"/validate-performance-v2-performanceprofile",
+		&webhook.Admission{Handler: &validator{nodeLister: nodeLister}},
+	)
+	return nil
+}
+
+func (v *validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	profileObj := &v2.PerformanceProfile{}
+	if err := v.decoder.Decode(req, profileObj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if err := profile.ValidateParametersAgainstCluster(profileObj, v.nodeLister); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	return admission.Allowed("")
+}
+
+// InjectDecoder is called by the controller-runtime webhook server to give
+// the validator access to the admission request decoder.
+func (v *validator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}